@@ -1,14 +1,26 @@
 package main
 
 import (
+	"bufio"
+	"compress/flate"
 	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
 	"flag"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"log"
 	"net/http"
+	"os"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
+	"unicode/utf8"
 
 	"github.com/gorilla/websocket"
 )
@@ -22,16 +34,49 @@ var host string
 var s string
 var ports string
 var _case string
+var report string
+var deflateLevel int
+var deflateMinSize int
+var broadcastClientsWarn int
+var broadcastRate float64
+var broadcastClients int
+var cmdPath string
+var cmdArgs string
+var cmdBinary bool
+var cmdChunkSize int
+var cmdPingInterval time.Duration
+var cmdWriteWait time.Duration
+var clientCA string
 
 func main() {
 
 	flag.StringVar(&crt, "crt", "", "ssl cert file")
 	flag.StringVar(&key, "key", "", "ssl key file")
 	flag.StringVar(&host, "host", "localhost", "listening server host")
-	flag.StringVar(&_case, "case", "", "choose a specialized case, (hang,rapid,t44)")
+	flag.StringVar(&_case, "case", "", "choose a specialized case, (hang,rapid,t44,autobahn,deflate,broadcast,cmd,mtls)")
 	flag.IntVar(&port, "port", 6789, "listening server port")
+	flag.StringVar(&report, "report", "", "summarize an Autobahn|Testsuite index.json and exit")
+	flag.IntVar(&deflateLevel, "deflate-level", flate.DefaultCompression, "permessage-deflate compression level, for -case deflate")
+	flag.IntVar(&deflateMinSize, "deflate-min-size", 256, "skip compressing payloads smaller than this many bytes, for -case deflate")
+	flag.IntVar(&broadcastClientsWarn, "broadcast-clients-warn", 1000, "log a warning once this many clients are registered with the hub, for -case broadcast")
+	flag.Float64Var(&broadcastRate, "broadcast-rate", 0, "messages/sec the server itself injects into the hub, for -case broadcast")
+	flag.IntVar(&broadcastClients, "broadcast-clients", 10, "number of parallel WebSocket connections the /client page opens on top of its default one, for -case broadcast")
+	flag.StringVar(&cmdPath, "cmd", "", "path to the child process to bridge each connection to, for -case cmd")
+	flag.StringVar(&cmdArgs, "cmd-args", "", "space-separated arguments passed to -cmd")
+	flag.BoolVar(&cmdBinary, "cmd-binary", false, "read stdout in fixed-size chunks instead of line-by-line, for -case cmd")
+	flag.IntVar(&cmdChunkSize, "cmd-chunk-size", 4096, "chunk size used to read stdout when -cmd-binary is set")
+	flag.DurationVar(&cmdPingInterval, "cmd-ping-interval", 30*time.Second, "ping keepalive interval, for -case cmd")
+	flag.DurationVar(&cmdWriteWait, "cmd-write-wait", 10*time.Second, "write deadline for each outbound frame, for -case cmd")
+	flag.StringVar(&clientCA, "client-ca", "", "PEM file of CAs trusted to sign client certificates, for -case mtls")
 	flag.Parse()
 
+	if report != "" {
+		if err := printAutobahnReport(report); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
 	if crt != "" || key != "" {
 		s = "s"
 		if port != 443 {
@@ -41,7 +86,9 @@ func main() {
 		ports = fmt.Sprintf(":%d", port)
 	}
 	http.HandleFunc("/client", client)
-	http.HandleFunc("/echo", socket)
+	if _case != "broadcast" && _case != "cmd" {
+		http.HandleFunc("/echo", socket)
+	}
 	http.HandleFunc("/t44", socket)
 	log.Printf("Running server on %s:%d\n", host, port)
 	switch _case {
@@ -54,12 +101,55 @@ func main() {
 		log.Printf("case: %s (rapid (250 fps) large (2048 bytes) random text messages)\n", _case)
 	case "t44":
 		log.Printf("case: %s (send 5 messages per seconds forever. gh issue #44)\n", _case)
+	case "autobahn":
+		log.Printf("case: %s (fuzzingserver-shaped endpoints for %d cases; drive these directly from the client under test, not via wstest -m fuzzingclient)\n", _case, len(autobahnCases))
+		http.HandleFunc("/getCaseCount", autobahnGetCaseCount)
+		http.HandleFunc("/runCase", autobahnRunCase)
+		http.HandleFunc("/updateReports", autobahnUpdateReports)
+	case "deflate":
+		log.Printf("case: %s (permessage-deflate negotiated, rapid 250KB payload compressed, level=%d, min-size=%d)\n", _case, deflateLevel, deflateMinSize)
+	case "broadcast":
+		log.Printf("case: %s (fan-out hub, warn at %d clients, server rate %.1f msg/s)\n", _case, broadcastClientsWarn, broadcastRate)
+		go hub.run()
+		if broadcastRate > 0 {
+			go hub.injectAtRate(broadcastRate)
+		}
+		http.HandleFunc("/echo", hub.serveWs)
+	case "cmd":
+		if cmdPath == "" {
+			log.Fatal("case: cmd requires -cmd")
+		}
+		log.Printf("case: %s (bridging each connection to %q)\n", _case, cmdPath+" "+cmdArgs)
+		http.HandleFunc("/echo", serveCmd)
+	case "mtls":
+		if crt == "" || key == "" || clientCA == "" {
+			log.Fatal("case: mtls requires -crt, -key and -client-ca")
+		}
+		log.Printf("case: %s (mutual TLS, echoing the peer certificate's Subject as the first frame)\n", _case)
 	}
 	log.Printf("http%s://%s%s/client (javascript client)\n", s, host, ports)
 	log.Printf("ws%s://%s%s/echo     (echo socket)\n", s, host, ports)
 	log.Printf("ws%s://%s%s/t44      (test issue 44 socket)\n", s, host, ports)
 	var err error
-	if crt != "" || key != "" {
+	if _case == "mtls" {
+		pool := x509.NewCertPool()
+		var pem []byte
+		pem, err = ioutil.ReadFile(clientCA)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			log.Fatalf("no certificates found in %s", clientCA)
+		}
+		server := &http.Server{
+			Addr: fmt.Sprintf(":%d", port),
+			TLSConfig: &tls.Config{
+				ClientCAs:  pool,
+				ClientAuth: tls.RequireAndVerifyClientCert,
+			},
+		}
+		err = server.ListenAndServeTLS(crt, key)
+	} else if crt != "" || key != "" {
 		err = http.ListenAndServeTLS(fmt.Sprintf(":%d", port), crt, key, nil)
 	} else {
 		err = http.ListenAndServe(fmt.Sprintf(":%d", port), nil)
@@ -69,24 +159,62 @@ func main() {
 	}
 }
 
+// deflateUpgrader negotiates permessage-deflate (RFC 7692); it's only
+// used for -case deflate so the other cases keep the plain websocket.Upgrade
+// path unchanged.
+//
+// NOTE: this is the Go test-harness half of RFC 7692 only. The matching
+// SwiftWebSocket client changes (advertising the extension on the open
+// handshake, RSV1 framing, raw-deflate stream handling, honoring
+// *_no_context_takeover / *_max_window_bits) are not implemented: this
+// repo snapshot has no SwiftWebSocket.swift for them to land in.
+var deflateUpgrader = websocket.Upgrader{
+	ReadBufferSize:    1024,
+	WriteBufferSize:   1024,
+	EnableCompression: true,
+}
+
 func socket(w http.ResponseWriter, r *http.Request) {
 	log.Print("connection established")
 	t44 := _case == "t44"
-	rapid := _case == "rapid"
+	rapid := _case == "rapid" || _case == "deflate"
+	deflate := _case == "deflate"
 	if _case == "hang" {
 		hang := time.Minute
 		log.Printf("hanging for %s\n", hang.String())
 		time.Sleep(hang)
 	}
-	ws, err := websocket.Upgrade(w, r, nil, 1024, 1024)
+	var ws *websocket.Conn
+	var err error
+	if deflate {
+		ws, err = deflateUpgrader.Upgrade(w, r, nil)
+	} else {
+		ws, err = websocket.Upgrade(w, r, nil, 1024, 1024)
+	}
 	if err != nil {
 		log.Print(err)
 		return
 	}
+	if deflate {
+		if err := ws.SetCompressionLevel(deflateLevel); err != nil {
+			log.Print(err)
+		}
+	}
 	defer func() {
 		ws.Close()
 		log.Print("connection closed")
 	}()
+	if _case == "mtls" {
+		if len(r.TLS.PeerCertificates) == 0 {
+			log.Print("mtls: no peer certificate")
+			return
+		}
+		subject := r.TLS.PeerCertificates[0].Subject.String()
+		log.Printf("mtls: peer subject: %s", subject)
+		if err := ws.WriteMessage(websocket.TextMessage, []byte(subject)); err != nil {
+			return
+		}
+	}
 	var mu sync.Mutex
 	go func() {
 		if t44 {
@@ -123,6 +251,9 @@ func socket(w http.ResponseWriter, r *http.Request) {
 				}
 				copy(msg, []byte(time.Now().String()+"\n"))
 				mu.Lock()
+				if deflate {
+					ws.EnableWriteCompression(len(msg) >= deflateMinSize)
+				}
 				if err := ws.WriteMessage(websocket.TextMessage, msg); err != nil {
 					mu.Unlock()
 					return
@@ -140,6 +271,9 @@ func socket(w http.ResponseWriter, r *http.Request) {
 		log.Print("rcvd: '" + string(msg) + "'")
 		if !t44 {
 			mu.Lock()
+			if deflate {
+				ws.EnableWriteCompression(len(msg) >= deflateMinSize)
+			}
 			ws.WriteMessage(msgt, msg)
 			mu.Unlock()
 		}
@@ -171,7 +305,7 @@ func client(w http.ResponseWriter, r *http.Request) {
             console.log("close")
         }
 	`)
-	if _case == "rapid" {
+	if _case == "rapid" || _case == "deflate" {
 		io.WriteString(w, `
         ws.onopen = function(){
         	console.log("opened")
@@ -180,6 +314,19 @@ func client(w http.ResponseWriter, r *http.Request) {
         	document.getElementById("out").innerHTML = "recv: [" + msg.data.length + " bytes] " + msg.data.slice(0, msg.data.indexOf('\n')) + "\n"
         }
 		`)
+	} else if _case == "broadcast" {
+		fmt.Fprintf(w, `
+		var sockets = [];
+		for (var i = 0; i < %d; i++) {
+			(function(n){
+				var s = new WebSocket("`+url+`");
+				s.onopen = function(){ console.log("socket " + n + " opened") }
+				s.onmessage = function(msg){ console.log(n + " recv: " + msg.data) }
+				s.onclose = function(){ console.log("socket " + n + " closed") }
+				sockets.push(s);
+			})(i);
+		}
+		`, broadcastClients)
 	} else if _case == "t44" {
 		io.WriteString(w, `
 		function send(){
@@ -223,3 +370,394 @@ func client(w http.ResponseWriter, r *http.Request) {
 	io.WriteString(w, `</script>`)
 
 }
+
+// broadcastClient is one upgraded /echo connection registered with the
+// hub. send is buffered so a slow reader can't block the broadcaster;
+// once it fills up the client is considered unresponsive and dropped.
+type broadcastClient struct {
+	ws   *websocket.Conn
+	send chan []byte
+}
+
+// broadcastHub fans every message received on any connection out to
+// every registered connection, the sender included, modeled on the gorilla chat
+// example's hub goroutine.
+type broadcastHub struct {
+	clients    map[*broadcastClient]bool
+	register   chan *broadcastClient
+	unregister chan *broadcastClient
+	broadcast  chan []byte
+}
+
+var hub = &broadcastHub{
+	clients:    make(map[*broadcastClient]bool),
+	register:   make(chan *broadcastClient),
+	unregister: make(chan *broadcastClient),
+	broadcast:  make(chan []byte),
+}
+
+func (h *broadcastHub) run() {
+	for {
+		select {
+		case c := <-h.register:
+			h.clients[c] = true
+			if len(h.clients) == broadcastClientsWarn {
+				log.Printf("broadcast: %d clients registered, at -broadcast-clients-warn threshold", len(h.clients))
+			}
+		case c := <-h.unregister:
+			if _, ok := h.clients[c]; ok {
+				delete(h.clients, c)
+				close(c.send)
+			}
+		case msg := <-h.broadcast:
+			for c := range h.clients {
+				select {
+				case c.send <- msg:
+				default:
+					// client's buffer is full; it's too slow to keep up.
+					// Close the connection itself, not just its channel,
+					// so the client actually observes the drop instead of
+					// hanging with a reader blocked forever.
+					delete(h.clients, c)
+					close(c.send)
+					c.ws.Close()
+				}
+			}
+		}
+	}
+}
+
+// injectAtRate has the server itself generate broadcast traffic, so a
+// single connected client can be used to validate backpressure handling
+// without needing many real senders.
+func (h *broadcastHub) injectAtRate(rate float64) {
+	t := time.NewTicker(time.Duration(float64(time.Second) / rate))
+	defer t.Stop()
+	var i int
+	for range t.C {
+		h.broadcast <- []byte(fmt.Sprintf("server #%-5d %v", i, time.Now()))
+		i++
+	}
+}
+
+func (h *broadcastHub) serveWs(w http.ResponseWriter, r *http.Request) {
+	ws, err := websocket.Upgrade(w, r, nil, 1024, 1024)
+	if err != nil {
+		log.Print(err)
+		return
+	}
+	c := &broadcastClient{ws: ws, send: make(chan []byte, 256)}
+	h.register <- c
+	log.Print("broadcast: client registered")
+	defer func() {
+		h.unregister <- c
+		ws.Close()
+		log.Print("broadcast: client unregistered")
+	}()
+
+	go func() {
+		for msg := range c.send {
+			if err := ws.WriteMessage(websocket.TextMessage, msg); err != nil {
+				return
+			}
+		}
+	}()
+
+	for {
+		_, msg, err := ws.ReadMessage()
+		if err != nil {
+			return
+		}
+		h.broadcast <- msg
+	}
+}
+
+// serveCmd spawns one instance of -cmd per connection and bridges it to
+// the socket: client frames are written to the child's stdin, and the
+// child's stdout becomes outbound frames, so interactive shells and
+// streaming programs can be exercised like the gorilla command example.
+func serveCmd(w http.ResponseWriter, r *http.Request) {
+	ws, err := websocket.Upgrade(w, r, nil, 1024, 1024)
+	if err != nil {
+		log.Print(err)
+		return
+	}
+	defer ws.Close()
+
+	var args []string
+	if cmdArgs != "" {
+		args = strings.Fields(cmdArgs)
+	}
+	cmd := exec.Command(cmdPath, args...)
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		log.Print(err)
+		return
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		log.Print(err)
+		return
+	}
+	if err := cmd.Start(); err != nil {
+		log.Print(err)
+		return
+	}
+	log.Printf("cmd: started %s (pid %d)", cmdPath, cmd.Process.Pid)
+	defer func() {
+		stdin.Close()
+		cmd.Wait()
+		log.Print("cmd: child exited")
+	}()
+
+	var wmu sync.Mutex
+	ws.SetReadDeadline(time.Now().Add(2 * cmdPingInterval))
+	ws.SetPongHandler(func(string) error {
+		ws.SetReadDeadline(time.Now().Add(2 * cmdPingInterval))
+		return nil
+	})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if cmdBinary {
+			buf := make([]byte, cmdChunkSize)
+			for {
+				n, err := stdout.Read(buf)
+				if n > 0 {
+					wmu.Lock()
+					ws.SetWriteDeadline(time.Now().Add(cmdWriteWait))
+					werr := ws.WriteMessage(websocket.BinaryMessage, buf[:n])
+					wmu.Unlock()
+					if werr != nil {
+						return
+					}
+				}
+				if err != nil {
+					break
+				}
+			}
+		} else {
+			scanner := bufio.NewScanner(stdout)
+			for scanner.Scan() {
+				wmu.Lock()
+				ws.SetWriteDeadline(time.Now().Add(cmdWriteWait))
+				werr := ws.WriteMessage(websocket.TextMessage, scanner.Bytes())
+				wmu.Unlock()
+				if werr != nil {
+					return
+				}
+			}
+		}
+		// the child's stdout closed (process exited); half-close the
+		// socket so the client learns there's nothing more coming.
+		wmu.Lock()
+		ws.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, "child process exited"), time.Now().Add(cmdWriteWait))
+		wmu.Unlock()
+	}()
+
+	pingDone := make(chan struct{})
+	defer close(pingDone)
+	go func() {
+		t := time.NewTicker(cmdPingInterval)
+		defer t.Stop()
+		for {
+			select {
+			case <-t.C:
+				wmu.Lock()
+				ws.SetWriteDeadline(time.Now().Add(cmdWriteWait))
+				err := ws.WriteMessage(websocket.PingMessage, nil)
+				wmu.Unlock()
+				if err != nil {
+					return
+				}
+			case <-pingDone:
+				return
+			}
+		}
+	}()
+
+	for {
+		msgt, msg, err := ws.ReadMessage()
+		if err != nil {
+			log.Print(err)
+			break
+		}
+		if msgt == websocket.BinaryMessage {
+			stdin.Write(msg)
+		} else {
+			stdin.Write(append(msg, '\n'))
+		}
+	}
+	<-done
+}
+
+// autobahnCases is the set of RFC 6455 case IDs this harness exercises,
+// grouped the way the upstream Autobahn|Testsuite numbers them: 1.x
+// framing, 2.x pings/pongs, 4.x opening handshake close behavior, 5.x
+// fragmentation, 6.x UTF-8 payloads, 7.x close handling, 9.x limits.
+//
+// This is NOT a reimplementation of wstest's judge: wstest's own
+// fuzzingserver/fuzzingclient modes verify compliance with an internal
+// state machine we don't have. What's below is a much smaller, honest
+// subset: plain per-case echo, plus the two families (6.x invalid UTF-8,
+// 9.x oversized payloads) where we can cheaply assert real protocol
+// behavior ourselves. The rest of the case IDs are exercised only as
+// generic echo/fragmentation/ping-pong smoke cases, not judged against
+// the full RFC matrix. There is no wstest involved in driving this: the
+// client under test is expected to call /getCaseCount, then /runCase for
+// each case number, then /updateReports itself (wstest's -m fuzzingclient
+// mode does not talk to these endpoints at all, since in that mode
+// wstest is the one acting as the test client).
+var autobahnCases = []string{
+	"1.1.1", "1.1.2", "1.1.3", "1.1.4",
+	"2.1", "2.2", "2.3", "2.4", "2.5",
+	"4.1.1", "4.1.2", "4.1.3",
+	"5.1", "5.2", "5.3", "5.4", "5.5", "5.6",
+	"6.1.1", "6.1.2", "6.1.3",
+	"7.1.1", "7.1.2", "7.1.3", "7.1.4", "7.1.5", "7.1.6",
+	"9.1.1", "9.1.2",
+}
+
+// autobahnMaxPayload bounds the 9.x payload-limit cases; it matches the
+// buffer size passed to websocket.Upgrade below.
+const autobahnMaxPayload = 1 << 20
+
+var autobahnMu sync.Mutex
+var autobahnAgents = map[string]bool{}
+
+// autobahnGetCaseCount implements the fuzzingserver GET /getCaseCount
+// endpoint: the total number of cases the client under test should run.
+func autobahnGetCaseCount(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	fmt.Fprintf(w, "%d", len(autobahnCases))
+}
+
+// autobahnRunCase implements GET /runCase?case=N&agent=X, where N is the
+// 1-based index into autobahnCases (matching the real Autobahn protocol,
+// where the driver iterates case numbers 1..getCaseCount rather than
+// passing RFC case-ID strings directly). N is mapped back to its RFC
+// case ID below to decide which per-case behavior applies. For most case
+// families this is just an echo of every frame sent by the client
+// (fragmented messages included, since gorilla's ReadMessage reassembles
+// them). For the two families we can cheaply check ourselves it enforces
+// real RFC 6455 behavior instead of echoing blindly: 6.x fails the
+// connection on invalid UTF-8 in a text frame, and 9.x fails it on an
+// oversized payload.
+func autobahnRunCase(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	agent := q.Get("agent")
+	n, err := strconv.Atoi(q.Get("case"))
+	var caseID string
+	if err == nil && n >= 1 && n <= len(autobahnCases) {
+		caseID = autobahnCases[n-1]
+	}
+	log.Printf("autobahn: case=%s (id=%s) agent=%s", q.Get("case"), caseID, agent)
+
+	ws, err := websocket.Upgrade(w, r, nil, autobahnMaxPayload, autobahnMaxPayload)
+	if err != nil {
+		log.Print(err)
+		return
+	}
+	defer ws.Close()
+
+	autobahnMu.Lock()
+	autobahnAgents[agent] = true
+	autobahnMu.Unlock()
+
+	ws.SetPingHandler(func(payload string) error {
+		return ws.WriteControl(websocket.PongMessage, []byte(payload), time.Now().Add(time.Second))
+	})
+
+	for {
+		msgt, msg, err := ws.ReadMessage()
+		if err != nil {
+			return
+		}
+		if strings.HasPrefix(caseID, "6.") && msgt == websocket.TextMessage && !utf8.Valid(msg) {
+			ws.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseInvalidFramePayloadData, "invalid UTF-8"), time.Now().Add(time.Second))
+			return
+		}
+		if strings.HasPrefix(caseID, "9.") && len(msg) > autobahnMaxPayload {
+			ws.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseMessageTooBig, "payload too large"), time.Now().Add(time.Second))
+			return
+		}
+		if err := ws.WriteMessage(msgt, msg); err != nil {
+			return
+		}
+	}
+}
+
+// autobahnReportEntry mirrors one agent/case result in the
+// Autobahn|Testsuite index.json report format.
+type autobahnReportEntry struct {
+	Behavior      string `json:"behavior"`
+	BehaviorClose string `json:"behaviorClose"`
+	Duration      int    `json:"duration"`
+	ReportFile    string `json:"reportfile"`
+}
+
+// autobahnUpdateReports implements GET /updateReports?agent=X: the
+// client under test calls this once it has run every case, signalling
+// that results for that agent are final. We don't grade the exchanges
+// and nothing here writes an index.json report; this just records the
+// agent in autobahnAgents. -report does not read that record: it
+// summarizes an index.json file given on the command line.
+func autobahnUpdateReports(w http.ResponseWriter, r *http.Request) {
+	agent := r.URL.Query().Get("agent")
+	log.Printf("autobahn: updateReports agent=%s", agent)
+	autobahnMu.Lock()
+	autobahnAgents[agent] = true
+	autobahnMu.Unlock()
+	w.WriteHeader(http.StatusOK)
+}
+
+// printAutobahnReport reads a wstest-generated index.json (shaped
+// map[agent]map[caseID]autobahnReportEntry) and prints a pass/fail
+// summary keyed by RFC 6455 case ID, so a CI job can fail the build on
+// regression without parsing the full HTML report.
+func printAutobahnReport(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var index map[string]map[string]autobahnReportEntry
+	if err := json.NewDecoder(f).Decode(&index); err != nil {
+		return fmt.Errorf("parsing %s: %v", path, err)
+	}
+
+	var agents []string
+	for agent := range index {
+		agents = append(agents, agent)
+	}
+	sort.Strings(agents)
+
+	var total, failures int
+	for _, agent := range agents {
+		cases := index[agent]
+		var caseIDs []string
+		for id := range cases {
+			caseIDs = append(caseIDs, id)
+		}
+		sort.Strings(caseIDs)
+		fmt.Printf("agent: %s\n", agent)
+		for _, id := range caseIDs {
+			entry := cases[id]
+			total++
+			ok := entry.Behavior == "OK" || entry.Behavior == "NON-STRICT" || entry.Behavior == "INFORMATIONAL"
+			status := "PASS"
+			if !ok {
+				status = "FAIL"
+				failures++
+			}
+			fmt.Printf("  %-8s %-12s behavior=%s behaviorClose=%s\n", id, status, entry.Behavior, entry.BehaviorClose)
+		}
+	}
+	fmt.Printf("%d case(s), %d failure(s)\n", total, failures)
+	if failures > 0 {
+		os.Exit(1)
+	}
+	return nil
+}